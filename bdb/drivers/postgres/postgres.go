@@ -0,0 +1,336 @@
+// Package postgres implements bdb.Interface for PostgreSQL by introspecting
+// pg_catalog directly, rather than information_schema. pg_catalog lets us
+// avoid the overhead information_schema imposes on large catalogs, and it
+// exposes things information_schema hides entirely: unique indexes that
+// aren't backed by a UNIQUE constraint, partial/expression indexes,
+// materialized views, and foreign tables.
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/wwt/sqlboiler/bdb"
+)
+
+// Driver holds the transient state needed to fulfil the bdb.Interface
+// against a PostgreSQL database.
+type Driver struct {
+	connStr string
+	schema  string
+	conn    *sql.DB
+}
+
+// New creates a new PostgreSQL driver that will introspect the named
+// schema (normally "public") once Open is called.
+func New(user, pass, dbname, host string, port int, sslmode, schema string) *Driver {
+	return &Driver{
+		schema: schema,
+		connStr: fmt.Sprintf(
+			"host=%s port=%d dbname=%s user=%s password=%s sslmode=%s",
+			host, port, dbname, user, pass, sslmode,
+		),
+	}
+}
+
+// Open opens the database connection.
+func (p *Driver) Open() error {
+	var err error
+	p.conn, err = sql.Open("postgres", p.connStr)
+	if err != nil {
+		return fmt.Errorf("unable to connect to postgres database: %s", err)
+	}
+	return nil
+}
+
+// Close closes the database connection.
+func (p *Driver) Close() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+}
+
+// relkinds that TableNames considers: ordinary table, view, materialized
+// view and foreign table.
+const tableRelKinds = "'r','v','m','f'"
+
+// TableNames returns the names of every table, view, materialized view
+// and foreign table visible in the configured schema.
+func (p *Driver) TableNames() ([]string, error) {
+	var names []string
+
+	query := fmt.Sprintf(`
+		select c.relname
+		from pg_catalog.pg_class c
+		join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+		where c.relkind in (%s)
+		and n.nspname = $1
+		order by c.relname`, tableRelKinds)
+
+	rows, err := p.conn.Query(query, p.schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// Columns returns the column metadata for tableName, including whether
+// each column is covered by a single-column unique index (see
+// uniqueSingleColumns).
+func (p *Driver) Columns(tableName string) ([]bdb.Column, error) {
+	query := `
+		select
+			a.attname as name,
+			pg_catalog.format_type(a.atttypid, a.atttypmod) as type,
+			coalesce(pg_catalog.col_description(a.attrelid, a.attnum), '') as comment,
+			not a.attnotnull as nullable,
+			coalesce(pg_catalog.pg_get_expr(ad.adbin, ad.adrelid), '') as default
+		from pg_catalog.pg_attribute a
+		join pg_catalog.pg_class c on c.oid = a.attrelid
+		join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+		left join pg_catalog.pg_attrdef ad on ad.adrelid = a.attrelid and ad.adnum = a.attnum
+		where a.attnum > 0
+		and not a.attisdropped
+		and n.nspname = $1
+		and c.relname = $2
+		order by a.attnum`
+
+	rows, err := p.conn.Query(query, p.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []bdb.Column
+	for rows.Next() {
+		var c bdb.Column
+		if err := rows.Scan(&c.Name, &c.DBType, &c.Comment, &c.Nullable, &c.Default); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	unique, err := p.uniqueSingleColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		columns[i].Unique = unique[columns[i].Name]
+		columns[i] = p.TranslateColumnType(columns[i])
+	}
+
+	return columns, nil
+}
+
+// uniqueSingleColumns returns the set of column names on tableName that
+// are the sole column of a unique index, via pg_index rather than
+// information_schema.table_constraints -- this also picks up unique
+// indexes that have no backing UNIQUE constraint, and correctly skips
+// multi-column unique indexes (which don't make any single column
+// unique on their own).
+//
+// Indexes that have any expression member (e.g. a functional index on
+// lower(email)) are excluded entirely rather than just dropping the
+// expression's key from the join: keeping the index's other, ordinary
+// columns around would misreport a composite "expr + col" unique index
+// as a single-column unique index on col alone.
+//
+// Partial indexes (ix.indpred is not null) are excluded too: a unique
+// index with a WHERE clause only guarantees uniqueness among the rows it
+// covers, not globally, so it must not be treated as an authoritative
+// Column.Unique the way an unconditional unique index is.
+func (p *Driver) uniqueSingleColumns(tableName string) (map[string]bool, error) {
+	query := `
+		select array_agg(a.attname order by k.ord)
+		from pg_catalog.pg_index ix
+		join pg_catalog.pg_class c on c.oid = ix.indrelid
+		join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+		join unnest(ix.indkey) with ordinality as k(attnum, ord) on true
+		join pg_catalog.pg_attribute a on a.attrelid = c.oid and a.attnum = k.attnum
+		where ix.indisunique
+		and not (0 = any(ix.indkey))
+		and ix.indpred is null
+		and n.nspname = $1
+		and c.relname = $2
+		group by ix.indexrelid`
+
+	rows, err := p.conn.Query(query, p.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes [][]string
+	for rows.Next() {
+		var cols pq.StringArray
+		if err := rows.Scan(&cols); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, []string(cols))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return singleColumnUniqueSets(indexes), nil
+}
+
+// singleColumnUniqueSets reduces a list of unique index column sets down
+// to the set of column names that are, on their own, the entirety of some
+// unique index. A column that only ever appears as part of a multi-column
+// unique index is not unique by itself.
+func singleColumnUniqueSets(indexes [][]string) map[string]bool {
+	unique := make(map[string]bool)
+	for _, cols := range indexes {
+		if len(cols) == 1 {
+			unique[cols[0]] = true
+		}
+	}
+	return unique
+}
+
+// PrimaryKeyInfo returns the primary key for tableName, or nil if it has
+// none.
+func (p *Driver) PrimaryKeyInfo(tableName string) (*bdb.PrimaryKey, error) {
+	query := `
+		select con.conname, array_agg(a.attname order by k.ord)
+		from pg_catalog.pg_constraint con
+		join pg_catalog.pg_class c on c.oid = con.conrelid
+		join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+		join unnest(con.conkey) with ordinality as k(attnum, ord) on true
+		join pg_catalog.pg_attribute a on a.attrelid = c.oid and a.attnum = k.attnum
+		where con.contype = 'p'
+		and n.nspname = $1
+		and c.relname = $2
+		group by con.conname`
+
+	var name string
+	var cols pq.StringArray
+	err := p.conn.QueryRow(query, p.schema, tableName).Scan(&name, &cols)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &bdb.PrimaryKey{Name: name, Columns: []string(cols)}, nil
+}
+
+// ForeignKeyInfo returns the foreign keys declared on tableName.
+func (p *Driver) ForeignKeyInfo(tableName string) ([]bdb.ForeignKey, error) {
+	query := `
+		select
+			con.conname,
+			af.attname as foreign_column,
+			a.attname as local_column,
+			fc.relname as foreign_table
+		from pg_catalog.pg_constraint con
+		join pg_catalog.pg_class c on c.oid = con.conrelid
+		join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+		join pg_catalog.pg_class fc on fc.oid = con.confrelid
+		join pg_catalog.pg_attribute a on a.attrelid = c.oid and a.attnum = con.conkey[1]
+		join pg_catalog.pg_attribute af on af.attrelid = fc.oid and af.attnum = con.confkey[1]
+		where con.contype = 'f'
+		and n.nspname = $1
+		and c.relname = $2`
+
+	rows, err := p.conn.Query(query, p.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fkeys []bdb.ForeignKey
+	for rows.Next() {
+		fk := bdb.ForeignKey{Table: tableName}
+		if err := rows.Scan(&fk.Name, &fk.ForeignColumn, &fk.Column, &fk.ForeignTable); err != nil {
+			return nil, err
+		}
+		fkeys = append(fkeys, fk)
+	}
+
+	return fkeys, rows.Err()
+}
+
+// TranslateColumnType converts a PostgreSQL type name, as reported by
+// format_type, to its Go equivalent, including the text[]/array and
+// domain-type cases pg_catalog lets us see clearly.
+func (p *Driver) TranslateColumnType(c bdb.Column) bdb.Column {
+	if strings.HasSuffix(c.DBType, "[]") {
+		elem := bdb.Column{DBType: strings.TrimSuffix(c.DBType, "[]"), Nullable: c.Nullable}
+		elem = p.TranslateColumnType(elem)
+		c.Type = "[]" + elem.Type
+		return c
+	}
+
+	dbType := p.resolveDomainBaseType(c.DBType)
+
+	switch dbType {
+	case "bigint", "bigserial":
+		c.Type = "int64"
+	case "integer", "serial":
+		c.Type = "int"
+	case "smallint", "smallserial":
+		c.Type = "int16"
+	case "double precision":
+		c.Type = "float64"
+	case "real":
+		c.Type = "float32"
+	case "boolean":
+		c.Type = "bool"
+	case "date", "timestamp without time zone", "timestamp with time zone":
+		c.Type = "time.Time"
+	default:
+		c.Type = "string"
+	}
+
+	if c.Nullable {
+		c.Type = "null." + c.Type
+	}
+
+	return c
+}
+
+// resolveDomainBaseType follows dbType through pg_type.typbasetype until it
+// finds a non-domain type, so a column declared against a domain (e.g.
+// "CREATE DOMAIN user_id AS integer") maps through to its base type
+// ("integer") rather than falling through TranslateColumnType's switch to
+// the string default. Non-domain types, and any type resolution fails to
+// look up, are returned unchanged.
+func (p *Driver) resolveDomainBaseType(dbType string) string {
+	seen := map[string]bool{}
+
+	for !seen[dbType] {
+		seen[dbType] = true
+
+		var base string
+		err := p.conn.QueryRow(`
+			select pg_catalog.format_type(t.typbasetype, -1)
+			from pg_catalog.pg_type t
+			where t.typname = $1
+			and t.typtype = 'd'`, dbType).Scan(&base)
+		if err != nil {
+			return dbType
+		}
+
+		dbType = base
+	}
+
+	return dbType
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package postgres
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+)
+
+// TestColumnsTranslatesTypes exercises Columns end to end against a real
+// database, proving that the Column.Type it returns is already translated
+// (e.g. "int", not the domain's name, for a column declared against an
+// integer domain; "[]null.string", not "text[]", for a nullable text[])
+// rather than the raw format_type() string -- TranslateColumnType is only
+// useful if something on the Columns() path actually calls it.
+//
+// Run with: SQLBOILER_TEST_DSN="host=... user=... dbname=..." go test -tags=integration ./...
+func TestColumnsTranslatesTypes(t *testing.T) {
+	dsn := os.Getenv("SQLBOILER_TEST_DSN")
+	if dsn == "" {
+		t.Skip("SQLBOILER_TEST_DSN not set, skipping postgres integration test")
+	}
+
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`drop table if exists translate_types_test`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`drop domain if exists translate_types_test_user_id`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`create domain translate_types_test_user_id as integer`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`
+		create table translate_types_test (
+			id translate_types_test_user_id not null,
+			tags text[] null
+		)`); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Exec(`drop table translate_types_test`)
+	defer conn.Exec(`drop domain translate_types_test_user_id`)
+
+	driver := &Driver{schema: "public", conn: conn}
+
+	columns, err := driver.Columns("translate_types_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, c := range columns {
+		got[c.Name] = c.Type
+	}
+
+	if got["id"] != "int" {
+		t.Errorf("expected domain column to translate to its base type int, got %q", got["id"])
+	}
+	if got["tags"] != "[]null.string" {
+		t.Errorf("expected array column to translate to []null.string, got %q", got["tags"])
+	}
+}
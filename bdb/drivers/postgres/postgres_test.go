@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSingleColumnUniqueSets(t *testing.T) {
+	t.Parallel()
+
+	indexes := [][]string{
+		{"email"},
+		{"tenant_id", "slug"},
+		{"id"},
+	}
+
+	got := singleColumnUniqueSets(indexes)
+
+	var names []string
+	for name := range got {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	want := []string{"email", "id"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected only single-column unique indexes to propagate, got: %#v", names)
+	}
+
+	if got["tenant_id"] || got["slug"] {
+		t.Error("columns belonging only to a composite unique index must not be marked unique")
+	}
+}
@@ -0,0 +1,322 @@
+// Package bdb defines the interface a database driver must implement so
+// that sqlboiler can introspect a schema, as well as the in-memory
+// representation of that schema (Table, Column, ForeignKey, ...) that the
+// code generator consumes.
+package bdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultPolymorphicTypeSuffix = "_type"
+	defaultPolymorphicIDSuffix   = "_id"
+)
+
+// Interface is what a database driver must implement to be used as the
+// source of schema information for the generator.
+type Interface interface {
+	TableNames() ([]string, error)
+	Columns(tableName string) ([]Column, error)
+	PrimaryKeyInfo(tableName string) (*PrimaryKey, error)
+	ForeignKeyInfo(tableName string) ([]ForeignKey, error)
+
+	// TranslateColumnType takes a Column and converts its database type to
+	// its Go variant, for example "varchar" to "string".
+	TranslateColumnType(Column) Column
+
+	Open() error
+	Close()
+}
+
+// Column holds information about a database column.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	DBType   string `json:"db_type"`
+	Default  string `json:"default,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+	Nullable bool   `json:"nullable"`
+	Unique   bool   `json:"unique"`
+}
+
+// PrimaryKey represents a primary key constraint on a table.
+type PrimaryKey struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// ForeignKey represents a foreign key constraint on a table.
+type ForeignKey struct {
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Column string `json:"column"`
+
+	Nullable bool `json:"nullable"`
+	Unique   bool `json:"unique"`
+
+	ForeignTable  string `json:"foreign_table"`
+	ForeignColumn string `json:"foreign_column"`
+
+	ForeignColumnNullable bool `json:"foreign_column_nullable"`
+	ForeignColumnUnique   bool `json:"foreign_column_unique"`
+}
+
+// ToManyRelationship describes a to-many relationship inferred from the
+// foreign end of another table's ForeignKey.
+type ToManyRelationship struct {
+	Column string `json:"column"`
+
+	ForeignTable  string `json:"foreign_table"`
+	ForeignColumn string `json:"foreign_column"`
+
+	ToJoinTable bool   `json:"to_join_table"`
+	JoinTable   string `json:"join_table,omitempty"`
+
+	JoinLocalColumn   string `json:"join_local_column,omitempty"`
+	JoinForeignColumn string `json:"join_foreign_column,omitempty"`
+}
+
+// ToOneRelationship describes a one-to-one relationship inferred from the
+// foreign end of another table's ForeignKey, where that key is constrained
+// to be unique (either it's that table's primary key, or has a unique
+// constraint of its own).
+type ToOneRelationship struct {
+	Column string `json:"column"`
+
+	ForeignTable  string `json:"foreign_table"`
+	ForeignColumn string `json:"foreign_column"`
+}
+
+// PolyRelationship describes a polymorphic relationship detected by
+// convention: a pair of columns, one holding the id of a row in another
+// table and the other holding the name of that table, e.g. "owner_id" and
+// "owner_type".
+type PolyRelationship struct {
+	Name       string `json:"name"`
+	Column     string `json:"column"`
+	TypeColumn string `json:"type_column"`
+}
+
+// Table metadata from the database schema.
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+
+	PKey  *PrimaryKey  `json:"primary_key,omitempty"`
+	FKeys []ForeignKey `json:"foreign_keys,omitempty"`
+
+	IsJoinTable bool `json:"is_join_table"`
+
+	ToManyRelationships      []ToManyRelationship `json:"to_many_relationships,omitempty"`
+	OneToOneRelationships    []ToOneRelationship  `json:"one_to_one_relationships,omitempty"`
+	PolymorphicRelationships []PolyRelationship   `json:"polymorphic_relationships,omitempty"`
+}
+
+// Tables uses an Interface to populate a list of Table structs, one per
+// table visible to the driver, along with the inferred relationships
+// between them.
+func Tables(exe Interface, overrides ...RelationshipOverrides) ([]Table, error) {
+	var err error
+	var names []string
+
+	if names, err = exe.TableNames(); err != nil {
+		return nil, fmt.Errorf("unable to get table names: %s", err)
+	}
+	sort.Strings(names)
+
+	var override RelationshipOverrides
+	if len(overrides) > 0 {
+		override = overrides[0]
+	}
+
+	tables := make([]Table, len(names))
+	for i, name := range names {
+		t := Table{Name: name}
+
+		if t.Columns, err = exe.Columns(name); err != nil {
+			return nil, fmt.Errorf("unable to fetch table column info (%s): %s", name, err)
+		}
+
+		if t.PKey, err = exe.PrimaryKeyInfo(name); err != nil {
+			return nil, fmt.Errorf("unable to fetch table pkey info (%s): %s", name, err)
+		}
+
+		if t.FKeys, err = exe.ForeignKeyInfo(name); err != nil {
+			return nil, fmt.Errorf("unable to fetch table fkey info (%s): %s", name, err)
+		}
+
+		t.FKeys = append(t.FKeys, override.synthesizeForeignKeys(name)...)
+
+		setIsJoinTable(&t)
+
+		tables[i] = t
+	}
+
+	for i := range tables {
+		setForeignKeyConstraints(&tables[i], tables)
+	}
+	for i := range tables {
+		setRelationships(&tables[i], tables)
+		tables[i].ToManyRelationships = append(tables[i].ToManyRelationships, override.synthesizeToManyRelationships(tables[i].Name)...)
+		tables[i].ToManyRelationships = append(tables[i].ToManyRelationships, override.synthesizeArrayRelationships(tables[i].Name)...)
+		setPolymorphicRelationships(&tables[i], override.PolymorphicTypeSuffix, override.PolymorphicIDSuffix)
+	}
+
+	return tables, nil
+}
+
+// setIsJoinTable marks a table as a join table when its primary key is
+// made up of exactly two columns, and both of those columns are also the
+// local columns of exactly two foreign keys.
+func setIsJoinTable(t *Table) {
+	if t.PKey == nil || len(t.PKey.Columns) != 2 || len(t.FKeys) != 2 {
+		return
+	}
+
+	for _, c := range t.PKey.Columns {
+		if findFKeyByColumn(t.FKeys, c) == nil {
+			return
+		}
+	}
+
+	t.IsJoinTable = true
+}
+
+// setForeignKeyConstraints fills in the Nullable/Unique flags on each of
+// table's foreign keys by looking at the local column, and ForeignColumn*
+// flags by looking at the referenced table's column.
+func setForeignKeyConstraints(t *Table, tables []Table) {
+	for i, fkey := range t.FKeys {
+		if c := findColumn(t.Columns, fkey.Column); c != nil {
+			t.FKeys[i].Nullable = c.Nullable
+			t.FKeys[i].Unique = c.Unique
+		}
+
+		foreignTable := findTable(tables, fkey.ForeignTable)
+		if foreignTable == nil {
+			continue
+		}
+
+		if c := findColumn(foreignTable.Columns, fkey.ForeignColumn); c != nil {
+			t.FKeys[i].ForeignColumnNullable = c.Nullable
+			t.FKeys[i].ForeignColumnUnique = c.Unique
+		}
+	}
+}
+
+// setRelationships derives t's to-many and to-one relationships by finding
+// every foreign key in every other table that points back at t. A foreign
+// key becomes a to-one relationship instead of a to-many one when it's
+// constrained to be unique, since then at most one row of the other table
+// can point back at any given row of t.
+func setRelationships(t *Table, tables []Table) {
+	for _, other := range tables {
+		if other.Name == t.Name {
+			continue
+		}
+
+		for _, fkey := range other.FKeys {
+			if fkey.ForeignTable != t.Name {
+				continue
+			}
+
+			if isToOneFKey(fkey, other) {
+				t.OneToOneRelationships = append(t.OneToOneRelationships, ToOneRelationship{
+					Column:        fkey.ForeignColumn,
+					ForeignTable:  other.Name,
+					ForeignColumn: fkey.Column,
+				})
+				continue
+			}
+
+			t.ToManyRelationships = append(t.ToManyRelationships, ToManyRelationship{
+				Column:        fkey.ForeignColumn,
+				ForeignTable:  other.Name,
+				ForeignColumn: fkey.Column,
+				ToJoinTable:   other.IsJoinTable,
+			})
+		}
+	}
+}
+
+// isToOneFKey reports whether fkey, a foreign key belonging to owner, is
+// constrained to point at no more than one row, either because its column
+// carries a unique constraint or because it is owner's single-column
+// primary key.
+func isToOneFKey(fkey ForeignKey, owner Table) bool {
+	if fkey.Unique {
+		return true
+	}
+
+	return owner.PKey != nil && len(owner.PKey.Columns) == 1 && owner.PKey.Columns[0] == fkey.Column
+}
+
+// setPolymorphicRelationships detects polymorphic relationships by
+// convention: any pair of columns "<name><idSuffix>" and
+// "<name><typeSuffix>" is recorded as a PolyRelationship naming the
+// discriminator column that holds the foreign table's name and the id
+// column that holds the foreign row's key. typeSuffix and idSuffix default
+// to "_type" and "_id" when empty.
+func setPolymorphicRelationships(t *Table, typeSuffix, idSuffix string) {
+	if typeSuffix == "" {
+		typeSuffix = defaultPolymorphicTypeSuffix
+	}
+	if idSuffix == "" {
+		idSuffix = defaultPolymorphicIDSuffix
+	}
+
+	for _, c := range t.Columns {
+		if !strings.HasSuffix(c.Name, typeSuffix) {
+			continue
+		}
+
+		name := strings.TrimSuffix(c.Name, typeSuffix)
+		idColumn := name + idSuffix
+		if findColumn(t.Columns, idColumn) == nil {
+			continue
+		}
+
+		t.PolymorphicRelationships = append(t.PolymorphicRelationships, PolyRelationship{
+			Name:       name,
+			Column:     idColumn,
+			TypeColumn: c.Name,
+		})
+	}
+}
+
+// DriverUsesLastInsertID returns true if the given driver name supports
+// retrieving the last inserted id via LastInsertId rather than a
+// RETURNING clause.
+func DriverUsesLastInsertID(driver string) bool {
+	return driver == "mysql"
+}
+
+func findColumn(columns []Column, name string) *Column {
+	for i := range columns {
+		if columns[i].Name == name {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
+func findTable(tables []Table, name string) *Table {
+	for i := range tables {
+		if tables[i].Name == name {
+			return &tables[i]
+		}
+	}
+	return nil
+}
+
+func findFKeyByColumn(fkeys []ForeignKey, column string) *ForeignKey {
+	for i := range fkeys {
+		if fkeys[i].Column == column {
+			return &fkeys[i]
+		}
+	}
+	return nil
+}
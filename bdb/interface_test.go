@@ -224,6 +224,77 @@ func TestSetRelationships(t *testing.T) {
 	}
 }
 
+func TestSetRelationshipsOneToOne(t *testing.T) {
+	t.Parallel()
+
+	tables := []Table{
+		{
+			Name: "one",
+			Columns: []Column{
+				{Name: "id", Type: "string"},
+			},
+		},
+		{
+			Name: "profile",
+			Columns: []Column{
+				{Name: "owner_id", Type: "string", Unique: true},
+			},
+			FKeys: []ForeignKey{{Column: "owner_id", ForeignTable: "one", ForeignColumn: "id", Unique: true}},
+		},
+	}
+
+	setRelationships(&tables[0], tables)
+	setRelationships(&tables[1], tables)
+
+	if got := len(tables[0].ToManyRelationships); got != 0 {
+		t.Error("should have no to many relationships:", got)
+	}
+	if got := len(tables[0].OneToOneRelationships); got != 1 {
+		t.Fatal("should have one to one relationship:", got)
+	}
+
+	rel := tables[0].OneToOneRelationships[0]
+	if rel.Column != "id" {
+		t.Error("wrong column:", rel.Column)
+	}
+	if rel.ForeignTable != "profile" {
+		t.Error("wrong table:", rel.ForeignTable)
+	}
+	if rel.ForeignColumn != "owner_id" {
+		t.Error("wrong column:", rel.ForeignColumn)
+	}
+}
+
+func TestSetPolymorphicRelationships(t *testing.T) {
+	t.Parallel()
+
+	table := Table{
+		Name: "comments",
+		Columns: []Column{
+			{Name: "id", Type: "string"},
+			{Name: "owner_id", Type: "string"},
+			{Name: "owner_type", Type: "string"},
+		},
+	}
+
+	setPolymorphicRelationships(&table, "", "")
+
+	if got := len(table.PolymorphicRelationships); got != 1 {
+		t.Fatal("should have one polymorphic relationship:", got)
+	}
+
+	rel := table.PolymorphicRelationships[0]
+	if rel.Name != "owner" {
+		t.Error("wrong name:", rel.Name)
+	}
+	if rel.Column != "owner_id" {
+		t.Error("wrong column:", rel.Column)
+	}
+	if rel.TypeColumn != "owner_type" {
+		t.Error("wrong type column:", rel.TypeColumn)
+	}
+}
+
 func TestDriverUsesLastInsertID(t *testing.T) {
 	t.Parallel()
 
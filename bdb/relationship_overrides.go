@@ -0,0 +1,153 @@
+package bdb
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RelationshipOverrides describes relationships that a schema doesn't
+// express through real foreign key constraints, but that the user wants
+// sqlboiler to generate code for anyway. It is loaded from a YAML or TOML
+// config file passed on the CLI and merged into the table graph returned
+// by Tables, as if the driver had reported the relationships itself.
+//
+// This covers array-typed "foreign keys" (e.g. a text[] column whose
+// elements each reference another table), cross-schema joins, and legacy
+// schemas that never had their constraints declared in the database.
+type RelationshipOverrides struct {
+	ForeignKeys []ForeignKeyOverride `yaml:"foreign_keys" toml:"foreign_keys"`
+	Aliases     []AliasOverride      `yaml:"aliases" toml:"aliases"`
+
+	// PolymorphicTypeSuffix and PolymorphicIDSuffix override the default
+	// "_type"/"_id" column name pattern used to detect polymorphic
+	// relationships by convention. Both are optional.
+	PolymorphicTypeSuffix string `yaml:"polymorphic_type_suffix" toml:"polymorphic_type_suffix"`
+	PolymorphicIDSuffix   string `yaml:"polymorphic_id_suffix" toml:"polymorphic_id_suffix"`
+}
+
+// ForeignKeyOverride declares a foreign key that the database doesn't
+// enforce, for example because the referencing column is an array type.
+//
+// When Array is true, Column holds an array of values (e.g. a text[] of
+// slugs) each of which should match ForeignColumn on some row of
+// ForeignTable, rather than a single scalar that matches at most one row.
+// That's an array-containment, one-to-many relationship, not a scalar
+// foreign key, so it synthesizes a ToManyRelationship on Table instead of
+// a ForeignKey entry.
+type ForeignKeyOverride struct {
+	Table         string `yaml:"table" toml:"table"`
+	Column        string `yaml:"column" toml:"column"`
+	ForeignTable  string `yaml:"foreign_table" toml:"foreign_table"`
+	ForeignColumn string `yaml:"foreign_column" toml:"foreign_column"`
+	Array         bool   `yaml:"array" toml:"array"`
+}
+
+// AliasOverride declares an additional to-many relationship on Table.
+// Name is read from the config, but it is not carried into the
+// synthesized ToManyRelationship, which has no name field: an alias that
+// repeats the columns of a real foreign key produces a ToManyRelationship
+// identical to the one derived from that key.
+type AliasOverride struct {
+	Table         string `yaml:"table" toml:"table"`
+	Name          string `yaml:"name" toml:"name"`
+	Column        string `yaml:"column" toml:"column"`
+	ForeignTable  string `yaml:"foreign_table" toml:"foreign_table"`
+	ForeignColumn string `yaml:"foreign_column" toml:"foreign_column"`
+}
+
+// LoadRelationshipOverrides parses data as a YAML or TOML relationship
+// override config, picking the format based on path's extension. It does
+// not read path itself; the caller supplies the file's contents.
+func LoadRelationshipOverrides(path string, data []byte) (RelationshipOverrides, error) {
+	var overrides RelationshipOverrides
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &overrides); err != nil {
+			return overrides, fmt.Errorf("unable to parse relationship config %s: %s", path, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &overrides); err != nil {
+			return overrides, fmt.Errorf("unable to parse relationship config %s: %s", path, err)
+		}
+	default:
+		return overrides, fmt.Errorf("unrecognized relationship config extension %q, want .yaml or .toml", ext)
+	}
+
+	return overrides, nil
+}
+
+// synthesizeForeignKeys returns the scalar ForeignKeys that should be
+// appended to table's FKeys as if the database had reported them. Array
+// overrides are excluded here; see synthesizeArrayRelationships.
+func (r RelationshipOverrides) synthesizeForeignKeys(table string) []ForeignKey {
+	var fkeys []ForeignKey
+	for _, o := range r.ForeignKeys {
+		if o.Table != table || o.Array {
+			continue
+		}
+		fkeys = append(fkeys, ForeignKey{
+			Table:         o.Table,
+			Name:          fmt.Sprintf("%s_%s_override_fkey", o.Table, o.Column),
+			Column:        o.Column,
+			ForeignTable:  o.ForeignTable,
+			ForeignColumn: o.ForeignColumn,
+		})
+	}
+	return fkeys
+}
+
+// synthesizeArrayRelationships returns the ToManyRelationships implied by
+// an array-typed ForeignKeyOverride that touches table, in either
+// direction: table has-many ForeignTable by array containment (e.g. posts
+// has-many tags), and symmetrically ForeignTable has-many table, since any
+// number of rows on the array-holding side can contain a given foreign
+// row's key (e.g. a tag can be referenced by many posts.tags arrays). That
+// makes the relationship navigable from both sides, the same as a
+// join-table M2M is.
+func (r RelationshipOverrides) synthesizeArrayRelationships(table string) []ToManyRelationship {
+	var rels []ToManyRelationship
+	for _, o := range r.ForeignKeys {
+		if !o.Array {
+			continue
+		}
+
+		if o.Table == table {
+			rels = append(rels, ToManyRelationship{
+				Column:        o.Column,
+				ForeignTable:  o.ForeignTable,
+				ForeignColumn: o.ForeignColumn,
+			})
+		}
+
+		if o.ForeignTable == table {
+			rels = append(rels, ToManyRelationship{
+				Column:        o.ForeignColumn,
+				ForeignTable:  o.Table,
+				ForeignColumn: o.Column,
+			})
+		}
+	}
+	return rels
+}
+
+// synthesizeToManyRelationships returns the extra ToManyRelationships that
+// should be appended to table's ToManyRelationships.
+func (r RelationshipOverrides) synthesizeToManyRelationships(table string) []ToManyRelationship {
+	var rels []ToManyRelationship
+	for _, o := range r.Aliases {
+		if o.Table != table {
+			continue
+		}
+		rels = append(rels, ToManyRelationship{
+			Column:        o.Column,
+			ForeignTable:  o.ForeignTable,
+			ForeignColumn: o.ForeignColumn,
+		})
+	}
+	return rels
+}
@@ -0,0 +1,130 @@
+package bdb
+
+import "testing"
+
+func TestLoadRelationshipOverrides(t *testing.T) {
+	t.Parallel()
+
+	yamlData := []byte(`
+foreign_keys:
+  - table: posts
+    column: tags
+    foreign_table: tags
+    foreign_column: slug
+    array: true
+aliases:
+  - table: products
+    name: deals
+    column: id
+    foreign_table: deals
+    foreign_column: product_id
+`)
+
+	overrides, err := LoadRelationshipOverrides("relationships.yaml", yamlData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(overrides.ForeignKeys) != 1 {
+		t.Fatalf("expected 1 foreign key override, got %d", len(overrides.ForeignKeys))
+	}
+	if overrides.ForeignKeys[0].ForeignColumn != "slug" {
+		t.Errorf("wrong foreign column: %s", overrides.ForeignKeys[0].ForeignColumn)
+	}
+	if !overrides.ForeignKeys[0].Array {
+		t.Error("expected the array flag to be set")
+	}
+
+	if len(overrides.Aliases) != 1 {
+		t.Fatalf("expected 1 alias override, got %d", len(overrides.Aliases))
+	}
+	if overrides.Aliases[0].Name != "deals" {
+		t.Errorf("wrong alias name: %s", overrides.Aliases[0].Name)
+	}
+}
+
+func TestSynthesizeForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	overrides := RelationshipOverrides{
+		ForeignKeys: []ForeignKeyOverride{
+			{Table: "posts", Column: "tags", ForeignTable: "tags", ForeignColumn: "slug"},
+		},
+	}
+
+	fkeys := overrides.synthesizeForeignKeys("posts")
+	if len(fkeys) != 1 {
+		t.Fatalf("expected 1 synthesized fkey, got %d", len(fkeys))
+	}
+	if fkeys[0].ForeignTable != "tags" || fkeys[0].ForeignColumn != "slug" {
+		t.Errorf("wrong synthesized fkey: %#v", fkeys[0])
+	}
+
+	if got := overrides.synthesizeForeignKeys("tags"); len(got) != 0 {
+		t.Errorf("expected no synthesized fkeys for unrelated table, got %d", len(got))
+	}
+}
+
+func TestSynthesizeArrayRelationships(t *testing.T) {
+	t.Parallel()
+
+	overrides := RelationshipOverrides{
+		ForeignKeys: []ForeignKeyOverride{
+			{Table: "posts", Column: "tags", ForeignTable: "tags", ForeignColumn: "slug", Array: true},
+		},
+	}
+
+	if got := overrides.synthesizeForeignKeys("posts"); len(got) != 0 {
+		t.Errorf("array overrides must not synthesize a scalar ForeignKey, got %#v", got)
+	}
+
+	rels := overrides.synthesizeArrayRelationships("posts")
+	if len(rels) != 1 {
+		t.Fatalf("expected 1 synthesized to-many relationship, got %d", len(rels))
+	}
+	if rels[0].Column != "tags" || rels[0].ForeignTable != "tags" || rels[0].ForeignColumn != "slug" {
+		t.Errorf("wrong synthesized relationship: %#v", rels[0])
+	}
+
+	reverse := overrides.synthesizeArrayRelationships("tags")
+	if len(reverse) != 1 {
+		t.Fatalf("expected 1 reciprocal to-many relationship, got %d", len(reverse))
+	}
+	if reverse[0].Column != "slug" || reverse[0].ForeignTable != "posts" || reverse[0].ForeignColumn != "tags" {
+		t.Errorf("wrong reciprocal relationship: %#v", reverse[0])
+	}
+
+	if got := overrides.synthesizeArrayRelationships("unrelated"); len(got) != 0 {
+		t.Errorf("expected no synthesized relationships for unrelated table, got %d", len(got))
+	}
+}
+
+func TestTablesWithRelationshipOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides := RelationshipOverrides{
+		ForeignKeys: []ForeignKeyOverride{
+			{Table: "table1", Column: "virt", ForeignTable: "table2", ForeignColumn: "col2"},
+		},
+	}
+
+	tables, err := Tables(testInterface{}, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table1 := findTable(tables, "table1")
+	if table1 == nil {
+		t.Fatal("missing table1")
+	}
+
+	found := false
+	for _, fk := range table1.FKeys {
+		if fk.Column == "virt" && fk.ForeignTable == "table2" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected synthesized foreign key on table1")
+	}
+}
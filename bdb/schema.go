@@ -0,0 +1,38 @@
+package bdb
+
+import "encoding/json"
+
+// SchemaVersion is bumped whenever SchemaDump's on-disk shape changes in a
+// way that isn't backwards compatible, so downstream tooling can tell
+// which layout it's reading.
+const SchemaVersion = 1
+
+// SchemaDump is a serializable snapshot of a table graph as returned by
+// Tables, intended for consumption by tooling that has no Go dependency on
+// sqlboiler itself (linters, diff tools, docs generators, codegens in
+// other languages).
+type SchemaDump struct {
+	Version int     `json:"version"`
+	Tables  []Table `json:"tables"`
+}
+
+// NewSchemaDump wraps tables in a SchemaDump stamped with the current
+// SchemaVersion.
+func NewSchemaDump(tables []Table) SchemaDump {
+	return SchemaDump{Version: SchemaVersion, Tables: tables}
+}
+
+// MarshalSchema serializes tables to its JSON schema-dump representation.
+func MarshalSchema(tables []Table) ([]byte, error) {
+	return json.MarshalIndent(NewSchemaDump(tables), "", "  ")
+}
+
+// UnmarshalSchema parses a JSON schema-dump document back into its table
+// graph.
+func UnmarshalSchema(data []byte) ([]Table, error) {
+	var dump SchemaDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, err
+	}
+	return dump.Tables, nil
+}
@@ -0,0 +1,86 @@
+package bdb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tables, err := Tables(testInterface{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalSchema(tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UnmarshalSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, tables) {
+		t.Errorf("schema did not round trip, got:\n%#v\nwant:\n%#v", got, tables)
+	}
+}
+
+// translatingInterface wraps testInterface the way a well-behaved driver
+// should: it translates each column's database type to its Go type as
+// part of introspection, instead of leaving Type as the raw driver type
+// string for callers to translate themselves (which nothing ever does).
+type translatingInterface struct {
+	testInterface
+}
+
+func (t translatingInterface) Columns(tableName string) ([]Column, error) {
+	cols, err := t.testInterface.Columns(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make([]Column, len(cols))
+	for i, c := range cols {
+		translated[i] = t.TranslateColumnType(c)
+	}
+	return translated, nil
+}
+
+// TestSchemaDumpHasTranslatedTypes proves that a schema dump consumed by
+// tooling with no Go dependency on sqlboiler sees generic translated types
+// ("string") rather than raw, driver-specific type strings
+// ("character varying") that only make sense to the driver that produced
+// them.
+func TestSchemaDumpHasTranslatedTypes(t *testing.T) {
+	t.Parallel()
+
+	tables, err := Tables(translatingInterface{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalSchema(tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "character varying") {
+		t.Error("schema dump should not contain raw driver type strings")
+	}
+	if !strings.Contains(string(data), `"type": "string"`) {
+		t.Error("schema dump should contain the translated Go type")
+	}
+}
+
+func TestSchemaVersionStamped(t *testing.T) {
+	t.Parallel()
+
+	dump := NewSchemaDump(nil)
+	if dump.Version != SchemaVersion {
+		t.Errorf("expected version %d, got %d", SchemaVersion, dump.Version)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wwt/sqlboiler/bdb"
+	"github.com/wwt/sqlboiler/bdb/drivers/postgres"
+)
+
+var (
+	dsDBUser        string
+	dsDBPass        string
+	dsDBName        string
+	dsDBHost        string
+	dsDBPort        int
+	dsDBSSLMode     string
+	dsDBSchema      string
+	dsOutput        string
+	dsRelationships string
+)
+
+var dumpSchemaCmd = &cobra.Command{
+	Use:   "dump-schema <driver>",
+	Short: "Introspect the database and write its schema graph out as JSON",
+	Long: "dump-schema runs the same introspection pipeline as code generation, but " +
+		"instead of emitting Go it serializes the resulting table graph to a single " +
+		"JSON document, so tooling that has no Go dependency on sqlboiler (linters, " +
+		"diff tools, docs generators, codegens in other languages) can consume it.",
+	Args: cobra.ExactArgs(1),
+	RunE: dumpSchema,
+}
+
+func init() {
+	flags := dumpSchemaCmd.Flags()
+	flags.StringVar(&dsDBUser, "user", "", "the database user")
+	flags.StringVar(&dsDBPass, "pass", "", "the database password")
+	flags.StringVar(&dsDBName, "dbname", "", "the database name")
+	flags.StringVar(&dsDBHost, "host", "localhost", "the database host")
+	flags.IntVar(&dsDBPort, "port", 5432, "the database port")
+	flags.StringVar(&dsDBSSLMode, "sslmode", "require", "the database sslmode")
+	flags.StringVar(&dsDBSchema, "schema", "public", "the database schema")
+	flags.StringVarP(&dsOutput, "output", "o", "", "file to write the schema dump to (default stdout)")
+	flags.StringVar(&dsRelationships, "relationships", "", "path to a YAML or TOML file of relationship overrides (see bdb.RelationshipOverrides)")
+}
+
+func dumpSchema(cmd *cobra.Command, args []string) error {
+	driverName := args[0]
+
+	var overrides bdb.RelationshipOverrides
+	if dsRelationships != "" {
+		data, err := ioutil.ReadFile(dsRelationships)
+		if err != nil {
+			return fmt.Errorf("unable to read relationships file: %s", err)
+		}
+
+		overrides, err = bdb.LoadRelationshipOverrides(dsRelationships, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	exe, err := driverFromName(driverName)
+	if err != nil {
+		return err
+	}
+
+	if err := exe.Open(); err != nil {
+		return fmt.Errorf("unable to connect to the database: %s", err)
+	}
+	defer exe.Close()
+
+	tables, err := bdb.Tables(exe, overrides)
+	if err != nil {
+		return fmt.Errorf("unable to fetch table data: %s", err)
+	}
+
+	data, err := bdb.MarshalSchema(tables)
+	if err != nil {
+		return fmt.Errorf("unable to marshal schema: %s", err)
+	}
+
+	if dsOutput == "" {
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+
+	return ioutil.WriteFile(dsOutput, data, 0644)
+}
+
+func driverFromName(name string) (bdb.Interface, error) {
+	switch name {
+	case "postgres":
+		return postgres.New(dsDBUser, dsDBPass, dsDBName, dsDBHost, dsDBPort, dsDBSSLMode, dsDBSchema), nil
+	default:
+		return nil, fmt.Errorf("dump-schema does not support driver %q", name)
+	}
+}
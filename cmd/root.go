@@ -0,0 +1,18 @@
+// Package cmd holds the sqlboiler CLI's cobra commands.
+package cmd
+
+import "github.com/spf13/cobra"
+
+var rootCmd = &cobra.Command{
+	Use:   "sqlboiler",
+	Short: "Generate ORM models tailored to your database schema",
+}
+
+func init() {
+	rootCmd.AddCommand(dumpSchemaCmd)
+}
+
+// Execute runs the sqlboiler root command.
+func Execute() error {
+	return rootCmd.Execute()
+}